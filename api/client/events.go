@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	"github.com/docker/docker/pkg/jsonmessage"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/parsers/filters"
+	"github.com/docker/docker/pkg/timeutils"
+)
+
+// CmdEvents streams real-time events from the daemon and prints them as
+// they arrive, until --until is hit or the user interrupts the command.
+//
+// Usage: docker events [OPTIONS]
+func (cli *DockerCli) CmdEvents(args ...string) error {
+	cmd := Cli.Subcmd("events", nil, "Get real time events from the server", true)
+	since := cmd.String([]string{"-since"}, "", "Show all events created since timestamp")
+	until := cmd.String([]string{"-until"}, "", "Stream events until this timestamp")
+	flFilter := opts.NewListOpts(nil)
+	cmd.Var(&flFilter, []string{"f", "-filter"}, "Filter output based on conditions provided")
+	cmd.Require(flag.Exact, 0)
+	if err := cmd.ParseFlags(args, true); err != nil {
+		return err
+	}
+
+	eventFilterArgs := filters.Args{}
+	for _, f := range flFilter.GetAll() {
+		var err error
+		eventFilterArgs, err = filters.ParseFlag(f, eventFilterArgs)
+		if err != nil {
+			return err
+		}
+	}
+
+	v := url.Values{}
+	if *since != "" {
+		sec, nsec, err := parseSinceUntil(*since)
+		if err != nil {
+			return err
+		}
+		v.Set("since", fmt.Sprintf("%d.%09d", sec, nsec))
+	}
+	if *until != "" {
+		sec, nsec, err := parseSinceUntil(*until)
+		if err != nil {
+			return err
+		}
+		v.Set("until", fmt.Sprintf("%d.%09d", sec, nsec))
+	}
+	if len(eventFilterArgs) > 0 {
+		filterJSON, err := filters.ToParam(eventFilterArgs)
+		if err != nil {
+			return err
+		}
+		v.Set("filters", filterJSON)
+	}
+
+	// Cancelling the request on Ctrl-C, rather than just returning, makes
+	// sure the daemon-side unsubscribe path actually runs.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	req, err := http.NewRequest("GET", cli.getAPIPath("/events", v), nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := cli.HTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error response from daemon: %s", resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		fmt.Fprintf(cli.out, "%s %s %s %s (from=%s)\n",
+			time.Unix(0, jm.TimeNano).Format(time.RFC3339Nano), jm.Type, jm.Status, jm.ID, jm.From)
+	}
+}
+
+// parseSinceUntil resolves a --since/--until value, accepted as either a
+// Unix (optionally fractional) timestamp or an RFC3339Nano string, to the
+// nanosecond pair used by the events API.
+func parseSinceUntil(value string) (sec, nsec int64, err error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.Unix(), int64(t.Nanosecond()), nil
+	}
+	return timeutils.ParseTimestamps(value)
+}
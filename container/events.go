@@ -0,0 +1,18 @@
+package container
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/docker/docker/engine"
+	"github.com/docker/docker/events"
+)
+
+// LogEvent records a container event on the events bus via the engine's
+// log_event job, so call sites don't need to know the job's name or
+// argument order.
+func LogEvent(eng *engine.Engine, action, id, from string) {
+	job := eng.Job("log_event", action, id, from, string(events.ContainerEventType))
+	if err := job.Run(); err != nil {
+		log.Errorf("Error logging event %s for %s: %s", action, id, err)
+	}
+}
@@ -5,28 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/engine"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/parsers/filters"
+	"github.com/docker/docker/pkg/pubsub"
+	"github.com/docker/docker/pkg/timeutils"
 )
 
-const eventsLimit = 64
-
-type listener chan<- *jsonmessage.JSONMessage
+const (
+	eventsLimit = 64
+	// bufferSize gives each subscriber enough headroom to absorb a burst of
+	// events without Log ever blocking on a slow reader.
+	bufferSize = 1024
+	// publishTimeout bounds how long Publish waits on any single subscriber
+	// before moving on to the next one.
+	publishTimeout = 100 * time.Millisecond
+)
 
 type Events struct {
-	mu          sync.RWMutex
-	events      []*jsonmessage.JSONMessage
-	subscribers []listener
+	mu     sync.Mutex
+	events []*jsonmessage.JSONMessage
+	pub    *pubsub.Publisher
 }
 
 func New() *Events {
 	return &Events{
 		events: make([]*jsonmessage.JSONMessage, 0, eventsLimit),
+		pub:    pubsub.NewPublisher(publishTimeout, bufferSize),
 	}
 }
 
@@ -36,6 +44,7 @@ func (e *Events) Install(eng *engine.Engine) error {
 	jobs := map[string]engine.Handler{
 		"events":            e.Get,
 		"log":               e.Log,
+		"log_event":         e.LogEvent,
 		"subscribers_count": e.SubscribersCount,
 	}
 	for name, job := range jobs {
@@ -47,42 +56,57 @@ func (e *Events) Install(eng *engine.Engine) error {
 }
 
 func (e *Events) Get(job *engine.Job) error {
-	var (
-		since   = job.GetenvInt64("since")
-		until   = job.GetenvInt64("until")
-		timeout = time.NewTimer(time.Unix(until, 0).Sub(time.Now()))
-	)
+	sinceStr := job.Getenv("since")
+	since, sinceNano, err := timeutils.ParseTimestamps(sinceStr)
+	if err != nil {
+		return err
+	}
+	until, untilNano, err := timeutils.ParseTimestamps(job.Getenv("until"))
+	if err != nil {
+		return err
+	}
+	timeout := time.NewTimer(time.Unix(until, untilNano).Sub(time.Now()))
 
 	eventFilters, err := filters.FromParam(job.Getenv("filters"))
 	if err != nil {
 		return err
 	}
+	ef := NewFilter(func(name string) string { return GetContainerId(job.Eng, name) }, eventFilters)
 
 	// If no until, disable timeout
-	if until == 0 {
+	if until == 0 && untilNano == 0 {
 		timeout.Stop()
 	}
 
-	listener := make(chan *jsonmessage.JSONMessage)
-	e.subscribe(listener)
-	defer e.unsubscribe(listener)
+	buffered, l, cancel := e.SubscribeTopic(since, sinceNano, ef)
+	defer cancel()
 
 	job.Stdout.Write(nil)
 
-	// Resend every event in the [since, until] time interval.
-	if since != 0 {
-		if err := e.writeCurrent(job, since, until, eventFilters); err != nil {
-			return err
+	// Resend every buffered event in the [since, until] time interval, but
+	// only when the caller actually asked for history; otherwise a plain
+	// `docker events` would replay the ring buffer before going live.
+	if sinceStr != "" {
+		for _, event := range buffered {
+			if beforeOrEqual(event.Time, event.TimeNano%int64(time.Second), until, untilNano) || (until == 0 && untilNano == 0) {
+				if err := writeEvent(job, event); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
 	for {
 		select {
-		case event, ok := <-listener:
+		case ev, ok := <-l:
 			if !ok {
 				return nil
 			}
-			if err := writeEvent(job, event, eventFilters); err != nil {
+			event, ok := ev.(*jsonmessage.JSONMessage)
+			if !ok {
+				continue
+			}
+			if err := writeEvent(job, event); err != nil {
 				return err
 			}
 		case <-timeout.C:
@@ -91,12 +115,24 @@ func (e *Events) Get(job *engine.Job) error {
 	}
 }
 
+// Log is a shim over LogEvent for the common case of a container event.
 func (e *Events) Log(job *engine.Job) error {
 	if len(job.Args) != 3 {
 		return fmt.Errorf("usage: %s ACTION ID FROM", job.Name)
 	}
 	// not waiting for receivers
-	go e.log(job.Args[0], job.Args[1], job.Args[2])
+	go e.log(job.Args[0], job.Args[1], job.Args[2], ContainerEventType)
+	return nil
+}
+
+// LogEvent records an event of an arbitrary EventType, e.g. image, network
+// or volume events, rather than assuming every event belongs to a container.
+func (e *Events) LogEvent(job *engine.Job) error {
+	if len(job.Args) != 4 {
+		return fmt.Errorf("usage: %s ACTION ID FROM TYPE", job.Name)
+	}
+	// not waiting for receivers
+	go e.log(job.Args[0], job.Args[1], job.Args[2], EventType(job.Args[3]))
 	return nil
 }
 
@@ -107,35 +143,7 @@ func (e *Events) SubscribersCount(job *engine.Job) error {
 	return nil
 }
 
-func writeEvent(job *engine.Job, event *jsonmessage.JSONMessage, eventFilters filters.Args) error {
-	isFiltered := func(field string, filter []string) bool {
-		if len(filter) == 0 {
-			return false
-		}
-		for _, v := range filter {
-			if v == field {
-				return false
-			}
-			if strings.Contains(field, ":") {
-				image := strings.Split(field, ":")
-				if image[0] == v {
-					return false
-				}
-			}
-		}
-		return true
-	}
-
-	//incoming container filter can be name,id or partial id, convert and replace as a full container id
-	for i, cn := range eventFilters["container"] {
-		eventFilters["container"][i] = GetContainerId(job.Eng, cn)
-	}
-
-	if isFiltered(event.Status, eventFilters["event"]) || isFiltered(event.From, eventFilters["image"]) ||
-		isFiltered(event.ID, eventFilters["container"]) {
-		return nil
-	}
-
+func writeEvent(job *engine.Job, event *jsonmessage.JSONMessage) error {
 	// When sending an event JSON serialization errors are ignored, but all
 	// other errors lead to the eviction of the listener.
 	if b, err := json.Marshal(event); err == nil {
@@ -146,31 +154,22 @@ func writeEvent(job *engine.Job, event *jsonmessage.JSONMessage, eventFilters fi
 	return nil
 }
 
-func (e *Events) writeCurrent(job *engine.Job, since, until int64, eventFilters filters.Args) error {
-	e.mu.RLock()
-	for _, event := range e.events {
-		if event.Time >= since && (event.Time <= until || until == 0) {
-			if err := writeEvent(job, event, eventFilters); err != nil {
-				e.mu.RUnlock()
-				return err
-			}
-		}
-	}
-	e.mu.RUnlock()
-	return nil
-}
-
 func (e *Events) subscribersCount() int {
-	e.mu.RLock()
-	c := len(e.subscribers)
-	e.mu.RUnlock()
-	return c
+	return e.pub.Len()
 }
 
-func (e *Events) log(action, id, from string) {
+func (e *Events) log(action, id, from string, eventType EventType) {
+	now := time.Now().UTC()
+	jm := &jsonmessage.JSONMessage{
+		Status:   action,
+		ID:       id,
+		From:     from,
+		Type:     string(eventType),
+		Time:     now.Unix(),
+		TimeNano: now.UnixNano(),
+	}
+
 	e.mu.Lock()
-	now := time.Now().UTC().Unix()
-	jm := &jsonmessage.JSONMessage{Status: action, ID: id, From: from, Time: now}
 	if len(e.events) == cap(e.events) {
 		// discard oldest event
 		copy(e.events, e.events[1:])
@@ -178,39 +177,60 @@ func (e *Events) log(action, id, from string) {
 	} else {
 		e.events = append(e.events, jm)
 	}
-	for _, s := range e.subscribers {
-		// We give each subscriber a 100ms time window to receive the event,
-		// after which we move to the next.
-		select {
-		case s <- jm:
-		case <-time.After(100 * time.Millisecond):
-		}
-	}
+	// Snapshot the subscriber set while still holding e.mu so that append
+	// and registration (SubscribeTopic also takes e.mu) stay atomic: a
+	// subscriber registered after this point can't also be in snap, so it
+	// won't double-receive jm, and one registered before it is guaranteed
+	// to be in snap and receive it live. The actual fan-out happens below,
+	// outside e.mu, so a slow subscriber only stalls other log() calls up
+	// to sending, never for the full publishTimeout.
+	snap := e.pub.Snapshot()
 	e.mu.Unlock()
-}
 
-func (e *Events) subscribe(l listener) {
-	e.mu.Lock()
-	e.subscribers = append(e.subscribers, l)
-	e.mu.Unlock()
+	e.pub.PublishTo(snap, jm)
 }
 
-// unsubscribe closes and removes the specified listener from the list of
-// previously registed ones.
-// It returns a boolean value indicating if the listener was successfully
-// found, closed and unregistered.
-func (e *Events) unsubscribe(l listener) bool {
+// SubscribeTopic registers a new listener on the publisher, restricted to
+// events accepted by ef, and returns every already-recorded event at or
+// after (since, sinceNano) that also matches ef, the channel that will
+// receive future matching events, and a cancel func to unregister the
+// listener.
+func (e *Events) SubscribeTopic(since, sinceNano int64, ef *Filter) ([]*jsonmessage.JSONMessage, chan interface{}, func()) {
 	e.mu.Lock()
-	for i, subscriber := range e.subscribers {
-		if subscriber == l {
-			close(l)
-			e.subscribers = append(e.subscribers[:i], e.subscribers[i+1:]...)
-			e.mu.Unlock()
-			return true
+	buffered := make([]*jsonmessage.JSONMessage, 0, len(e.events))
+	for _, event := range e.events {
+		if atOrAfter(event.Time, event.TimeNano%int64(time.Second), since, sinceNano) && ef.Include(event) {
+			buffered = append(buffered, event)
 		}
 	}
+	l := e.pub.SubscribeTopic(func(v interface{}) bool {
+		event, ok := v.(*jsonmessage.JSONMessage)
+		return ok && ef.Include(event)
+	})
 	e.mu.Unlock()
-	return false
+
+	cancel := func() {
+		e.pub.Evict(l)
+	}
+	return buffered, l, cancel
+}
+
+// atOrAfter reports whether (sec, nsec) is at or after (sinceSec, sinceNsec)
+// when compared as a (seconds, nanoseconds) pair, so that events recorded
+// within the same wall-clock second are still ordered correctly.
+func atOrAfter(sec, nsec, sinceSec, sinceNsec int64) bool {
+	if sec != sinceSec {
+		return sec > sinceSec
+	}
+	return nsec >= sinceNsec
+}
+
+// beforeOrEqual reports whether (sec, nsec) is at or before (untilSec, untilNsec).
+func beforeOrEqual(sec, nsec, untilSec, untilNsec int64) bool {
+	if sec != untilSec {
+		return sec < untilSec
+	}
+	return nsec <= untilNsec
 }
 
 func GetContainerId(eng *engine.Engine, name string) string {
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/parsers/filters"
+)
+
+func TestSubscribeTopicOnlyReceivesMatchingEventsUnderLoad(t *testing.T) {
+	e := New()
+
+	ef := NewFilter(identity, filters.Args{"container": {"keepme"}})
+	_, l, cancel := e.SubscribeTopic(0, 0, ef)
+	defer cancel()
+
+	const n = 2000
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < n; i++ {
+			if i%2 == 0 {
+				e.log("create", "keepme", "busybox", ContainerEventType)
+			} else {
+				e.log("create", "dropme", "busybox", ContainerEventType)
+			}
+		}
+		close(done)
+	}()
+
+	received := 0
+	timeout := time.After(5 * time.Second)
+	for received < n/2 {
+		select {
+		case v := <-l:
+			jm, ok := v.(*jsonmessage.JSONMessage)
+			if !ok || jm.ID != "keepme" {
+				t.Fatalf("received event for non-matching container: %v", v)
+			}
+			received++
+		case <-timeout:
+			t.Fatalf("timed out waiting for events, got %d/%d", received, n/2)
+		}
+	}
+
+	<-done
+
+	select {
+	case v, ok := <-l:
+		if ok {
+			t.Fatalf("received unexpected event from a non-matching container: %v", v)
+		}
+	case <-time.After(50 * time.Millisecond):
+	}
+}
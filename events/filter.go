@@ -0,0 +1,65 @@
+package events
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/parsers/filters"
+)
+
+// Filter is a precomputed, subscription-time snapshot of an event=/image=/
+// container= filter set. Resolving container names or partial IDs to full
+// container IDs happens once, here, rather than on every event that passes
+// through the bus.
+type Filter struct {
+	actions    []string
+	images     []string
+	containers []string
+	types      []string
+}
+
+// NewFilter builds a Filter from raw filter Args, resolving any
+// container= values (name, partial or full ID) to full container IDs via
+// resolveContainerID.
+func NewFilter(resolveContainerID func(name string) string, ef filters.Args) *Filter {
+	f := &Filter{
+		actions: ef["event"],
+		images:  ef["image"],
+		types:   ef["type"],
+	}
+	for _, cn := range ef["container"] {
+		f.containers = append(f.containers, resolveContainerID(cn))
+	}
+	return f
+}
+
+// Include reports whether event matches the filter. A nil Filter matches
+// everything.
+func (f *Filter) Include(event *jsonmessage.JSONMessage) bool {
+	if f == nil {
+		return true
+	}
+	return matches(event.Status, f.actions) && matches(event.From, f.images) &&
+		matches(event.ID, f.containers) && matches(event.Type, f.types)
+}
+
+// matches reports whether field satisfies filter, with an empty filter
+// matching everything and a "repo:tag" field also matching on its bare
+// repo name.
+func matches(field string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, v := range filter {
+		if v == field {
+			return true
+		}
+		if strings.Contains(field, ":") {
+			repo := strings.SplitN(field, ":", 2)[0]
+			if repo == v {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/parsers/filters"
+)
+
+func TestFilterMatchesRepoWithoutTag(t *testing.T) {
+	ef := NewFilter(identity, filters.Args{"image": {"busybox"}})
+	if !ef.Include(&jsonmessage.JSONMessage{From: "busybox:latest"}) {
+		t.Fatal("expected image filter \"busybox\" to match \"busybox:latest\"")
+	}
+	if ef.Include(&jsonmessage.JSONMessage{From: "busybox2:latest"}) {
+		t.Fatal("expected image filter \"busybox\" not to match \"busybox2:latest\"")
+	}
+}
+
+func TestFilterResolvesPartialContainerIds(t *testing.T) {
+	resolve := func(name string) string {
+		if name == "abc" {
+			return "abc123456789"
+		}
+		return ""
+	}
+	ef := NewFilter(resolve, filters.Args{"container": {"abc"}})
+	if !ef.Include(&jsonmessage.JSONMessage{ID: "abc123456789"}) {
+		t.Fatal("expected partial container id \"abc\" to resolve and match the full id")
+	}
+	if ef.Include(&jsonmessage.JSONMessage{ID: "abc"}) {
+		t.Fatal("the unresolved partial id itself should not match")
+	}
+}
+
+func TestFilterDropsUnresolvableContainers(t *testing.T) {
+	ef := NewFilter(func(string) string { return "" }, filters.Args{"container": {"nosuchcontainer"}})
+	if ef.Include(&jsonmessage.JSONMessage{ID: "anything"}) {
+		t.Fatal("a filter with only unresolvable containers should match nothing")
+	}
+}
+
+func TestFilterMatchesEventType(t *testing.T) {
+	ef := NewFilter(identity, filters.Args{"type": {string(ImageEventType)}})
+	if !ef.Include(&jsonmessage.JSONMessage{Type: string(ImageEventType)}) {
+		t.Fatal("expected type filter \"image\" to match an image event")
+	}
+	if ef.Include(&jsonmessage.JSONMessage{Type: string(ContainerEventType)}) {
+		t.Fatal("expected type filter \"image\" not to match a container event")
+	}
+}
+
+func identity(name string) string { return name }
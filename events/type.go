@@ -0,0 +1,14 @@
+package events
+
+// EventType identifies which subsystem an event belongs to, so that
+// consumers can subscribe to e.g. only image events via a `type=image`
+// filter without having to infer it from the shape of Status/From/ID.
+type EventType string
+
+const (
+	ContainerEventType EventType = "container"
+	ImageEventType     EventType = "image"
+	NetworkEventType   EventType = "network"
+	VolumeEventType    EventType = "volume"
+	DaemonEventType    EventType = "daemon"
+)
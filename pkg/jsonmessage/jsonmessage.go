@@ -0,0 +1,12 @@
+package jsonmessage
+
+// JSONMessage defines a message struct used for JSON streaming output,
+// including both build/pull progress updates and daemon events.
+type JSONMessage struct {
+	Status   string `json:"status,omitempty"`
+	ID       string `json:"id,omitempty"`
+	From     string `json:"from,omitempty"`
+	Time     int64  `json:"time,omitempty"`
+	TimeNano int64  `json:"timeNano,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
@@ -0,0 +1,90 @@
+package filters
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Args stores filter key to values map.
+// It's not possible to get values by multiple keys of the same name.
+type Args map[string][]string
+
+// ErrBadFormat is an error returned when a filter is not in the right format
+var ErrBadFormat = errors.New("bad format of filter (expected name=value)")
+
+// ParseFlag parses a key=value string and adds it to prev.
+func ParseFlag(arg string, prev Args) (Args, error) {
+	filters := prev
+	if len(arg) == 0 {
+		return filters, nil
+	}
+
+	if !strings.Contains(arg, "=") {
+		return filters, ErrBadFormat
+	}
+
+	f := strings.SplitN(arg, "=", 2)
+
+	name := strings.ToLower(strings.TrimSpace(f[0]))
+	value := strings.TrimSpace(f[1])
+
+	filters = filters.Add(name, value)
+
+	return filters, nil
+}
+
+// Add adds a new value to a filter field.
+func (filters Args) Add(name, value string) Args {
+	if _, ok := filters[name]; ok {
+		filters[name] = append(filters[name], value)
+	} else {
+		filters[name] = []string{value}
+	}
+	return filters
+}
+
+// ToParam packs the Args into a string for easy transport from client to server.
+func ToParam(a Args) (string, error) {
+	if len(a) == 0 {
+		return "", nil
+	}
+
+	buf, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// FromParam unpacks the filter Args.
+func FromParam(p string) (Args, error) {
+	args := Args{}
+	if len(p) == 0 {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(p), &args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// Match returns true if any value given for field, taken as a regular
+// expression, matches source, or if no values were given for field at all.
+func (filters Args) Match(field, source string) bool {
+	fieldValues := filters[field]
+	if len(fieldValues) == 0 {
+		return true
+	}
+	for _, name2match := range fieldValues {
+		match, err := regexp.MatchString(name2match, source)
+		if err != nil {
+			continue
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// Publisher is a simple pub/sub mechanism. Subscribers are registered with
+// Subscribe, and receive messages via the channel that method returns.
+// Callers are responsible for unregistering via Evict once they no longer
+// want to receive messages.
+type Publisher struct {
+	m           sync.RWMutex
+	buffer      int
+	timeout     time.Duration
+	subscribers map[subscriber]topicFunc
+}
+
+type subscriber chan interface{}
+
+type topicFunc func(v interface{}) bool
+
+// NewPublisher creates a new Publisher. publishTimeout is the maximum time
+// a Publish call will wait on any single slow subscriber before giving up
+// on it for that message. buffer sets the capacity of each subscriber's
+// channel so that a burst of messages doesn't immediately fall back to the
+// timeout path.
+func NewPublisher(publishTimeout time.Duration, buffer int) *Publisher {
+	return &Publisher{
+		buffer:      buffer,
+		timeout:     publishTimeout,
+		subscribers: make(map[subscriber]topicFunc),
+	}
+}
+
+// Len returns the number of current subscribers.
+func (p *Publisher) Len() int {
+	p.m.RLock()
+	i := len(p.subscribers)
+	p.m.RUnlock()
+	return i
+}
+
+// Subscribe registers a new subscriber that receives every published value.
+func (p *Publisher) Subscribe() chan interface{} {
+	return p.SubscribeTopic(nil)
+}
+
+// SubscribeTopic registers a new subscriber that only receives values for
+// which topic returns true. A nil topic matches everything.
+func (p *Publisher) SubscribeTopic(topic topicFunc) chan interface{} {
+	ch := make(chan interface{}, p.buffer)
+	p.m.Lock()
+	p.subscribers[ch] = topic
+	p.m.Unlock()
+	return ch
+}
+
+// Evict removes the specified subscriber from the publisher, closing its
+// channel.
+func (p *Publisher) Evict(sub chan interface{}) {
+	p.m.Lock()
+	delete(p.subscribers, sub)
+	close(sub)
+	p.m.Unlock()
+}
+
+// Publish sends v to every current subscriber whose topic matches. Each
+// subscriber gets up to the configured timeout to receive the value before
+// Publish moves on, so a single slow subscriber can't block the others or
+// the caller indefinitely.
+func (p *Publisher) Publish(v interface{}) {
+	p.PublishTo(p.Snapshot(), v)
+}
+
+// Snapshot is an opaque, point-in-time copy of the subscriber set, safe to
+// range over without holding any Publisher lock. Callers that need to keep
+// registration (Subscribe/SubscribeTopic) atomic with some external state
+// (e.g. an events log append) can take a Snapshot while holding their own
+// lock and then fan out via PublishTo once it's released.
+type Snapshot struct {
+	subscribers map[subscriber]topicFunc
+}
+
+// Snapshot copies the current subscriber map under p.m's own lock.
+func (p *Publisher) Snapshot() Snapshot {
+	p.m.RLock()
+	defer p.m.RUnlock()
+	subscribers := make(map[subscriber]topicFunc, len(p.subscribers))
+	for sub, topic := range p.subscribers {
+		subscribers[sub] = topic
+	}
+	return Snapshot{subscribers: subscribers}
+}
+
+// PublishTo sends v to every subscriber in snap whose topic matches, with
+// the same per-subscriber timeout as Publish. It does not touch p.m, so it
+// can safely be called after releasing a lock that was held while taking
+// snap.
+func (p *Publisher) PublishTo(snap Snapshot, v interface{}) {
+	wg := new(sync.WaitGroup)
+	for sub, topic := range snap.subscribers {
+		wg.Add(1)
+		go p.sendTopic(sub, topic, v, wg)
+	}
+	wg.Wait()
+}
+
+func (p *Publisher) sendTopic(sub subscriber, topic topicFunc, v interface{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+	// sub may have been evicted, and its channel closed, after the snapshot
+	// was taken; the send then panics and v is dropped for that subscriber.
+	defer func() { recover() }()
+	if topic != nil && !topic(v) {
+		return
+	}
+
+	select {
+	case sub <- v:
+	case <-time.After(p.timeout):
+	}
+}
+
+// Close shuts down the publisher, closing every current subscriber channel.
+func (p *Publisher) Close() {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for sub := range p.subscribers {
+		close(sub)
+	}
+	p.subscribers = make(map[subscriber]topicFunc)
+}
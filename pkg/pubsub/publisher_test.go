@@ -0,0 +1,97 @@
+package pubsub
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribeTopicOnlyReceivesMatchingValues(t *testing.T) {
+	p := NewPublisher(100*time.Millisecond, 16)
+	l := p.SubscribeTopic(func(v interface{}) bool { return v.(int)%2 == 0 })
+	defer p.Evict(l)
+
+	for i := 0; i < 10; i++ {
+		p.Publish(i)
+	}
+
+	for want := 0; want < 10; want += 2 {
+		select {
+		case v := <-l:
+			if v.(int) != want {
+				t.Fatalf("got %v, want %v", v, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v", want)
+		}
+	}
+
+	select {
+	case v, ok := <-l:
+		if ok {
+			t.Fatalf("received unexpected odd value: %v", v)
+		}
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEvict(t *testing.T) {
+	p := NewPublisher(100*time.Millisecond, 1)
+	l := p.Subscribe()
+
+	p.Evict(l)
+
+	if _, ok := <-l; ok {
+		t.Fatalf("expected channel to be closed after Evict")
+	}
+	if n := p.Len(); n != 0 {
+		t.Fatalf("expected 0 subscribers after Evict, got %d", n)
+	}
+}
+
+// TestPublishSubscribeEvictConcurrently exercises Publish, SubscribeTopic
+// and Evict concurrently with a slow subscriber in the mix, and checks that
+// they all complete in time: the slow subscriber must not hold up
+// registration and eviction, and a subscriber evicted while a Publish is
+// fanning out to it must not crash that Publish.
+func TestPublishSubscribeEvictConcurrently(t *testing.T) {
+	p := NewPublisher(20*time.Millisecond, 64)
+
+	// A permanently slow subscriber that never drains its channel: with the
+	// old Publish (fan-out under p.m), this alone would make every other
+	// Publish/SubscribeTopic/Evict call wait out the full timeout.
+	slow := p.Subscribe()
+	defer p.Evict(slow)
+
+	const rounds = 200
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			p.Publish(i)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			l := p.SubscribeTopic(nil)
+			p.Evict(l)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Publish/SubscribeTopic/Evict did not complete in time")
+	}
+}
@@ -0,0 +1,32 @@
+package timeutils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseTimestamps parses value as a "%d.%09d" (seconds.nanoseconds) style
+// timestamp, as produced by time.Unix(sec, nsec). The nanosecond portion is
+// optional, and if present but not exactly 9 digits long it is scaled to
+// nanoseconds. An empty value returns 0, 0.
+func ParseTimestamps(value string) (sec, nsec int64, err error) {
+	if value == "" {
+		return 0, 0, nil
+	}
+	sa := strings.SplitN(value, ".", 2)
+	sec, err = strconv.ParseInt(sa[0], 10, 64)
+	if err != nil {
+		return sec, 0, err
+	}
+	if len(sa) != 2 {
+		return sec, 0, nil
+	}
+	nsec, err = strconv.ParseInt(sa[1], 10, 64)
+	if err != nil {
+		return sec, nsec, err
+	}
+	// sa[1] is "nanoseconds" but may not be 9 digits long, so scale it.
+	nsec = int64(float64(nsec) * math.Pow(10, float64(9-len(sa[1]))))
+	return sec, nsec, nil
+}
@@ -0,0 +1,33 @@
+package timeutils
+
+import "testing"
+
+func TestParseTimestamps(t *testing.T) {
+	cases := []struct {
+		value    string
+		sec      int64
+		nsec     int64
+		expError bool
+	}{
+		{"", 0, 0, false},
+		{"1136214245", 1136214245, 0, false},
+		{"1136214245.999999999", 1136214245, 999999999, false},
+		{"1136214245.5", 1136214245, 500000000, false},
+		{"not-a-number", 0, 0, true},
+	}
+	for _, c := range cases {
+		sec, nsec, err := ParseTimestamps(c.value)
+		if c.expError {
+			if err == nil {
+				t.Errorf("ParseTimestamps(%q): expected an error", c.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseTimestamps(%q): unexpected error: %v", c.value, err)
+		}
+		if sec != c.sec || nsec != c.nsec {
+			t.Errorf("ParseTimestamps(%q) = (%d, %d), want (%d, %d)", c.value, sec, nsec, c.sec, c.nsec)
+		}
+	}
+}